@@ -2,117 +2,303 @@ package chip
 
 import (
 	"fmt"
-	"math"
 	"math/rand"
+	"sync"
 	"time"
 
-	"github.com/gdamore/tcell"
+	chipio "github.com/luddd3/chip8/chip/io"
+)
+
+const (
+	loResWidth  = 64
+	loResHeight = 32
+	hiResWidth  = 128
+	hiResHeight = 64
+
+	fontOffset      = 0x000 // 16 low-res digits, 5 bytes each
+	hiResFontOffset = 0x050 // 10 high-res digits, 10 bytes each
+
+	// defaultClockHz is the CPU instruction rate used when Options.ClockHz
+	// is left at zero. 500Hz is the rate most CHIP-8 ROMs were tuned
+	// against.
+	defaultClockHz = 500
+	// timerHz is the fixed rate dt/st decrement at, per the CHIP-8 spec.
+	// Unlike the CPU clock this is never configurable.
+	timerHz = 60
 )
 
 type Chip struct {
-	screen     tcell.Screen
-	memory     []byte   // 4096 bytes
-	display    []byte   // 64x32 bytes (pixels)
-	stack      []uint16 // 16 16-bit values
-	v          []byte   // 16 8-bit registers Vx (0-F)
-	dt         byte     // 8-bit register for delay timer (decremented at 60Hz)
-	st         byte     // 8-bit register for sound timer (decremented at 60Hz)
-	keys       []byte
-	i          uint16 // 16-bit register, only lowest 12 bit are used
-	pc         uint16 // 16-bit program counter
-	sp         byte   // 8-bit stack pointer
-	currentKey byte   // currently pressed key
-	drawFlag   bool   // should display be drawn
+	frontend chipio.Frontend
+	mode     Mode
+	quirks   Quirks
+	clockHz  int
+
+	// mu protects all register and memory state below from concurrent
+	// access by the CPU goroutine (Step, via Cycle's CPU ticker) and the
+	// timer goroutine (Cycle's 60Hz timer ticker).
+	mu sync.Mutex
+
+	memory []byte // 4096 bytes
+	// planes holds the display buffers, one per XO-CHIP bit plane. SChip
+	// and Chip8 only ever draw to/clear planes[0]. Each buffer is sized
+	// for the largest resolution (128x64) regardless of the current mode
+	// so switching between lo-res and hi-res never needs a reallocation.
+	planes [2][]byte
+	plane  byte // bitmask of planes selected by Fx01; bit0 = plane 0
+	hires  bool // false = 64x32, true = 128x64
+
+	// prevFrame is the per-cell plane bitmask draw() last sent to the
+	// frontend, sized like planes so switching resolutions never needs a
+	// reallocation. prevWidth/prevHeight record the resolution it was
+	// built at; draw() treats every cell as dirty the first time it runs
+	// at a new resolution, since the same index means a different (x, y)
+	// once width changes.
+	prevFrame             []byte
+	prevWidth, prevHeight int
+
+	stack []uint16 // 16 16-bit values
+	v     []byte   // 16 8-bit registers Vx (0-F)
+	dt    byte     // 8-bit register for delay timer (decremented at 60Hz)
+	st    byte     // 8-bit register for sound timer (decremented at 60Hz)
+	keys  []byte
+	i     uint16 // 16-bit register, only lowest 12 bit are used
+	pc    uint16 // 16-bit program counter
+	sp    byte   // 8-bit stack pointer
+
+	currentKey byte // currently pressed key
+	drawFlag   bool // should display be drawn
+	halted     bool // set by 00FD (EXIT), stops Cycle
+
+	// waitingKey is true while waitKey is blocked on keyPress, i.e.
+	// between an Fx0A starting to wait and it being satisfied. KeyDown
+	// only sends to keyPress while this is true, so a key pressed during
+	// ordinary gameplay (before any Fx0A runs) is dropped instead of
+	// sitting in the channel and being handed to a later, unrelated
+	// Fx0A as a stale result.
+	waitingKey bool
+	// keyPress backs the blocking Fx0A wait: KeyDown sends the freshly
+	// pressed key here once waitingKey is set, and waitKey receives it.
+	// Buffered by 1 so KeyDown's send (made with c.mu held) never blocks
+	// on waitKey actually reaching its receive.
+	keyPress chan byte
+
+	audioPattern [16]byte // XO-CHIP Fx02 pattern buffer
+
+	rom     []byte // last ROM passed to LoadRom, kept around for Reset
+	romName string // name LoadRom was given, used to key RPL save files (Fx75/Fx85)
+
+	// breakpoints, atBreak and resume back the chip/debug package's
+	// step/continue/break commands; see debug.go. They're unused (and
+	// awaitBreakpoint a no-op) for callers that never call
+	// AddBreakpoint.
+	breakpoints map[uint16]bool
+	atBreak     chan uint16
+	resume      chan struct{}
+	done        chan struct{}
+}
+
+var fontSet = []byte{
+	0xF0, 0x90, 0x90, 0x90, 0xF0, // 0
+	0x20, 0x60, 0x20, 0x20, 0x70, // 1
+	0xF0, 0x10, 0xF0, 0x80, 0xF0, // 2
+	0xF0, 0x10, 0xF0, 0x10, 0xF0, // 3
+	0x90, 0x90, 0xF0, 0x10, 0x10, // 4
+	0xF0, 0x80, 0xF0, 0x10, 0xF0, // 5
+	0xF0, 0x80, 0xF0, 0x90, 0xF0, // 6
+	0xF0, 0x10, 0x20, 0x40, 0x40, // 7
+	0xF0, 0x90, 0xF0, 0x90, 0xF0, // 8
+	0xF0, 0x90, 0xF0, 0x10, 0xF0, // 9
+	0xF0, 0x90, 0xF0, 0x90, 0x90, // A
+	0xE0, 0x90, 0xE0, 0x90, 0xE0, // B
+	0xF0, 0x80, 0x80, 0x80, 0xF0, // C
+	0xE0, 0x90, 0x90, 0x90, 0xE0, // D
+	0xF0, 0x80, 0xF0, 0x80, 0xF0, // E
+	0xF0, 0x80, 0xF0, 0x80, 0x80, // F
 }
 
-var keyMap = map[rune]byte{
-	'1': 1,  // 1
-	'2': 2,  // 2
-	'3': 3,  // 3
-	'4': 4,  // 4
-	'Q': 5,  // Q
-	'W': 6,  // W
-	'E': 7,  // E
-	'R': 8,  // R
-	'A': 9,  // A
-	'S': 10, // S
-	'D': 11, // D
-	'F': 12, // F
-	'Z': 13, // Z
-	'X': 14, // X
-	'C': 15, // C
-	'V': 16, // V
+// hiResFontSet is the SChip 10-byte-per-digit big font used by Fx30,
+// covering digits 0-9.
+var hiResFontSet = []byte{
+	0x3C, 0x7E, 0xE7, 0xC3, 0xC3, 0xC3, 0xC3, 0xE7, 0x7E, 0x3C, // 0
+	0x18, 0x38, 0x58, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x3C, // 1
+	0x3E, 0x7F, 0xC3, 0x06, 0x0C, 0x18, 0x30, 0x60, 0xFF, 0xFF, // 2
+	0x3C, 0x7E, 0xC3, 0x03, 0x0E, 0x0E, 0x03, 0xC3, 0x7E, 0x3C, // 3
+	0x06, 0x0E, 0x1E, 0x36, 0x66, 0xC6, 0xFF, 0xFF, 0x06, 0x06, // 4
+	0xFF, 0xFF, 0xC0, 0xFC, 0xFE, 0x03, 0x03, 0xC3, 0x7E, 0x3C, // 5
+	0x3E, 0x7C, 0xC0, 0xFC, 0xFE, 0xC3, 0xC3, 0xC3, 0x7E, 0x3C, // 6
+	0xFF, 0xFF, 0x03, 0x06, 0x0C, 0x18, 0x30, 0x30, 0x30, 0x30, // 7
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7E, 0x7E, 0xC3, 0xC3, 0x7E, 0x3C, // 8
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7F, 0x3F, 0x03, 0xC3, 0x7E, 0x3C, // 9
 }
 
-func New(screen tcell.Screen) *Chip {
+func New(frontend chipio.Frontend, opts Options) *Chip {
 	memory := make([]byte, 4096)
-	display := make([]byte, 64*32)
-	fontSet := []byte{
-		0xF0, 0x90, 0x90, 0x90, 0xF0, // 0
-		0x20, 0x60, 0x20, 0x20, 0x70, // 1
-		0xF0, 0x10, 0xF0, 0x80, 0xF0, // 2
-		0xF0, 0x10, 0xF0, 0x10, 0xF0, // 3
-		0x90, 0x90, 0xF0, 0x10, 0x10, // 4
-		0xF0, 0x80, 0xF0, 0x10, 0xF0, // 5
-		0xF0, 0x80, 0xF0, 0x90, 0xF0, // 6
-		0xF0, 0x10, 0x20, 0x40, 0x40, // 7
-		0xF0, 0x90, 0xF0, 0x90, 0xF0, // 8
-		0xF0, 0x90, 0xF0, 0x10, 0xF0, // 9
-		0xF0, 0x90, 0xF0, 0x90, 0x90, // A
-		0xE0, 0x90, 0xE0, 0x90, 0xE0, // B
-		0xF0, 0x80, 0x80, 0x80, 0xF0, // C
-		0xE0, 0x90, 0x90, 0x90, 0xE0, // D
-		0xF0, 0x80, 0xF0, 0x80, 0xF0, // E
-		0xF0, 0x80, 0xF0, 0x80, 0x80, // F
-	}
 	for i, b := range fontSet {
-		memory[i] = b
+		memory[fontOffset+i] = b
+	}
+	for i, b := range hiResFontSet {
+		memory[hiResFontOffset+i] = b
 	}
 
-	return &Chip{
-		screen:   screen,
+	quirks := opts.Quirks
+	if opts.UseDefaultQuirks {
+		quirks = DefaultQuirks(opts.Mode)
+	}
+
+	clockHz := opts.ClockHz
+	if clockHz == 0 {
+		clockHz = defaultClockHz
+	}
+
+	c := &Chip{
+		frontend: frontend,
+		mode:     opts.Mode,
+		quirks:   quirks,
+		clockHz:  clockHz,
 		memory:   memory,
-		display:  display,
-		v:        make([]byte, 16),
-		dt:       0,
-		st:       0,
-		stack:    make([]uint16, 16),
-		keys:     make([]byte, 16),
-		drawFlag: false,
-		pc:       0x200,
-		sp:       0,
+		planes: [2][]byte{
+			make([]byte, hiResWidth*hiResHeight),
+			make([]byte, hiResWidth*hiResHeight),
+		},
+		prevFrame: make([]byte, hiResWidth*hiResHeight),
+		plane:     1,
+		v:         make([]byte, 16),
+		dt:        0,
+		st:        0,
+		stack:     make([]uint16, 16),
+		keys:      make([]byte, 16),
+		keyPress:  make(chan byte, 1),
+		drawFlag:  false,
+		pc:        0x200,
+		sp:        0,
+
+		breakpoints: make(map[uint16]bool),
+		atBreak:     make(chan uint16),
+		resume:      make(chan struct{}),
+		done:        make(chan struct{}),
 	}
+	frontend.Resize(c.width(), c.height())
+	return c
 }
 
-func (c *Chip) LoadRom(rom []byte) {
+// LoadRom copies rom into memory at 0x200. name identifies the ROM for
+// RPL save files (Fx75/Fx85); pass "" if the caller has no meaningful
+// name, and rplFile falls back to a shared "default" file.
+func (c *Chip) LoadRom(name string, rom []byte) {
+	c.romName = name
+	c.rom = rom
 	for i, b := range rom {
 		c.memory[0x200+i] = b
 	}
 }
 
-func (c *Chip) KeyDown(char rune) {
-	idx := keyMap[char]
-	c.keys[idx] = 1
-	c.currentKey = idx
+// KeyDown marks CHIP-8 keypad key (0x0-0xF) as pressed. Frontends are
+// responsible for translating their own physical keys/scancodes onto this
+// range before calling in. It takes c.mu since it's typically called from
+// a frontend's own polling goroutine, concurrently with Cycle's reads of
+// c.keys/c.currentKey.
+func (c *Chip) KeyDown(key byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys[key] == 0 && c.waitingKey {
+		// Only an up-to-down transition satisfies a pending Fx0A wait;
+		// key-repeat while already held must not re-trigger it. And only
+		// deliver it once, to whichever Fx0A is actually waiting right
+		// now: waitingKey is false for any keypress that happens outside
+		// of a waitKey call.
+		c.waitingKey = false
+		c.keyPress <- key
+	}
+	c.keys[key] = 1
+	c.currentKey = key
 }
 
-func (c *Chip) KeyUp(char rune) {
-	idx := keyMap[char]
-	c.keys[idx] = 0
+// KeyUp marks key as released. See KeyDown on c.mu.
+func (c *Chip) KeyUp(key byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keys[key] = 0
 	c.currentKey = 0
 }
 
+func (c *Chip) width() int {
+	if c.hires {
+		return hiResWidth
+	}
+	return loResWidth
+}
+
+func (c *Chip) height() int {
+	if c.hires {
+		return hiResHeight
+	}
+	return loResHeight
+}
+
+// Cycle drives the interpreter until halted (00FD) or its ROM panics on an
+// unrecognized opcode. It runs two independent tickers: the CPU clock, at
+// c.clockHz, executes one instruction via Step; a fixed 60Hz timer clock
+// decrements dt/st and drives the frontend's Audio while st > 0. Callers
+// typically run Cycle in its own goroutine.
 func (c *Chip) Cycle() {
+	defer close(c.done)
+
+	cpuTicker := time.NewTicker(time.Second / time.Duration(c.clockHz))
+	defer cpuTicker.Stop()
+	timerTicker := time.NewTicker(time.Second / timerHz)
+	defer timerTicker.Stop()
+
 	for {
-		err := c.nextOp()
-		if err != nil {
-			panic(err)
+		select {
+		case <-cpuTicker.C:
+			if c.halted {
+				return
+			}
+			if err := c.Step(); err != nil {
+				panic(err)
+			}
+		case <-timerTicker.C:
+			c.tickTimers()
 		}
+	}
+}
 
-		if c.drawFlag {
-			c.draw()
-		}
-		time.Sleep(1 * time.Millisecond)
+// Step executes a single instruction and, if it set drawFlag, hands the
+// frame to the frontend. Cycle calls this at clockHz; callers that want to
+// drive the interpreter manually, e.g. tests or a debugger, can call it
+// directly instead of Cycle. If a breakpoint is set at the current PC,
+// Step blocks on AtBreak/Continue before executing it.
+func (c *Chip) Step() error {
+	c.awaitBreakpoint(c.pc)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.nextOp(); err != nil {
+		return err
+	}
+	if c.drawFlag {
+		c.draw()
+	}
+	return nil
+}
+
+// tickTimers decrements dt and st once, 60 times a second, and starts or
+// stops the frontend's audio tone to match st.
+func (c *Chip) tickTimers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dt > 0 {
+		c.dt--
+	}
+	if c.st > 0 {
+		c.st--
+		c.frontend.Start()
+	} else {
+		c.frontend.Stop()
 	}
 }
 
@@ -122,6 +308,11 @@ func (c *Chip) nextOp() error {
 
 	switch opcode & 0xF000 {
 	case 0x0000:
+		if opcode&0x00F0 == 0x00C0 && c.mode != Chip8 {
+			// SCD n (00Cn): scroll display down n lines
+			c.scrollDown(byte(opcode & 0x000F))
+			break
+		}
 		switch opcode & 0x0FFF {
 		// CLS (00E0)
 		case 0x00E0:
@@ -134,6 +325,40 @@ func (c *Chip) nextOp() error {
 			c.pc = c.stack[c.sp]
 			c.sp--
 			break
+		// SCR (00FB): scroll display right 4 pixels
+		case 0x00FB:
+			if c.mode != Chip8 {
+				c.scrollRight()
+			}
+			break
+		// SCL (00FC): scroll display left 4 pixels
+		case 0x00FC:
+			if c.mode != Chip8 {
+				c.scrollLeft()
+			}
+			break
+		// EXIT (00FD): exit the interpreter
+		case 0x00FD:
+			if c.mode != Chip8 {
+				c.halted = true
+			}
+			break
+		// LOW (00FE): disable hi-res mode
+		case 0x00FE:
+			if c.mode != Chip8 {
+				c.hires = false
+				c.frontend.Resize(c.width(), c.height())
+				c.drawFlag = true
+			}
+			break
+		// HIGH (00FF): enable 128x64 hi-res mode
+		case 0x00FF:
+			if c.mode != Chip8 {
+				c.hires = true
+				c.frontend.Resize(c.width(), c.height())
+				c.drawFlag = true
+			}
+			break
 		// SYS addr (0nnn)
 		default:
 			// Jump to a machine code routine at nnn. It is ignored by modern
@@ -169,13 +394,31 @@ func (c *Chip) nextOp() error {
 			c.pc += 2
 		}
 		break
-	// SE Vx, Vy (5xy0)
 	case 0x5000:
-		// Skip next instruction if Vx == Vy
 		x := c.memory[pc] & 0x0F
-		y := (c.memory[pc+1] & 0xF0) >> 2
-		if c.v[x] == c.v[y] {
-			c.pc += 2
+		y := (c.memory[pc+1] & 0xF0) >> 4
+		switch opcode & 0x000F {
+		// SE Vx, Vy (5xy0)
+		case 0x0000:
+			// Skip next instruction if Vx == Vy
+			if c.v[x] == c.v[y] {
+				c.pc += 2
+			}
+			break
+		// LD [I], Vx-Vy (5xy2): XO-CHIP register range store
+		case 0x0002:
+			if c.mode == XOChip {
+				c.storeRange(x, y)
+			}
+			break
+		// LD Vx-Vy, [I] (5xy3): XO-CHIP register range load
+		case 0x0003:
+			if c.mode == XOChip {
+				c.loadRange(x, y)
+			}
+			break
+		default:
+			return unrecognizedOpcode(opcode)
 		}
 		break
 	// LD Vx, byte (6xkk)
@@ -192,7 +435,7 @@ func (c *Chip) nextOp() error {
 		break
 	case 0x8000:
 		x := c.memory[pc] & 0x0F
-		y := c.memory[pc+1] & 0xF0 >> 2
+		y := c.memory[pc+1] & 0xF0 >> 4
 		switch opcode & 0x000F {
 		// LD Vx, Vy (8xy0)
 		case 0x0000:
@@ -238,12 +481,16 @@ func (c *Chip) nextOp() error {
 		// SHR Vx, {, Vy} (8xy6)
 		case 0x0006:
 			// Set Vx = Vx SHR 1
-			if c.v[x]&0b00000001 == 1 {
+			src := y
+			if c.quirks.ShiftVxOnly {
+				src = x
+			}
+			if c.v[src]&0b00000001 == 1 {
 				c.v[0xF] = 1
 			} else {
 				c.v[0xF] = 0
 			}
-			c.v[x] >>= 1
+			c.v[x] = c.v[src] >> 1
 			break
 		// SUBN Vx, Vy (8xy7)
 		case 0x0007:
@@ -258,12 +505,16 @@ func (c *Chip) nextOp() error {
 		// SHL Vx, Vy (8xyE)
 		case 0x000E:
 			// Set Vx = Vx SHL 1
-			if c.v[x]&0b10000000 == 1 {
+			src := y
+			if c.quirks.ShiftVxOnly {
+				src = x
+			}
+			if c.v[src]&0b10000000 == 1 {
 				c.v[0xF] = 1
 			} else {
 				c.v[0xF] = 0
 			}
-			c.v[x] <<= 1
+			c.v[x] = c.v[src] << 1
 			break
 		default:
 			return unrecognizedOpcode(opcode)
@@ -272,7 +523,7 @@ func (c *Chip) nextOp() error {
 	// SNE Vx, Vy (9xy0)
 	case 0x9000:
 		x := c.memory[pc] & 0x0F
-		y := (c.memory[pc+1] & 0xF0) >> 2
+		y := (c.memory[pc+1] & 0xF0) >> 4
 		if c.v[x] != c.v[y] {
 			c.pc += 2
 		}
@@ -284,8 +535,13 @@ func (c *Chip) nextOp() error {
 		break
 	// JP V0, addr (Bnnn)
 	case 0xB000:
-		// Jump to location nnn + V0
-		c.pc = (opcode & 0x0FFF) + uint16(c.v[0])
+		// Jump to location nnn + V0 (or xnn + Vx, under the JumpWithVx quirk)
+		if c.quirks.JumpWithVx {
+			x := (opcode & 0x0F00) >> 8
+			c.pc = (opcode & 0x0FFF) + uint16(c.v[x])
+		} else {
+			c.pc = (opcode & 0x0FFF) + uint16(c.v[0])
+		}
 		break
 	// RND Vx, byte (Cxkk)
 	case 0xC000:
@@ -298,9 +554,14 @@ func (c *Chip) nextOp() error {
 		// Display n-byte sprite starting at memory location I at (Vx, Vy),
 		// set VF = collision
 		x := c.memory[pc] & 0x0F
-		y := (c.memory[pc+1] & 0xF0) >> 2
+		y := (c.memory[pc+1] & 0xF0) >> 4
 		n := c.memory[pc+1] & 0x0F
-		c.displaySprite(c.v[x], c.v[y], byte(c.i), byte(n))
+		if n == 0 && c.mode != Chip8 {
+			// DRW Vx, Vy, 0 (Dxy0): SChip 16x16 sprite
+			c.displaySprite16(c.v[x], c.v[y])
+		} else {
+			c.displaySprite(c.v[x], c.v[y], byte(c.i), byte(n))
+		}
 		break
 	case 0xE000:
 		switch opcode & 0x00FF {
@@ -324,6 +585,11 @@ func (c *Chip) nextOp() error {
 			return unrecognizedOpcode(opcode)
 		}
 	case 0xF000:
+		if opcode == 0xF000 && c.mode == XOChip {
+			// LD I, nnnn (F000 NNNN): load the following 16-bit word into I
+			c.i = uint16(c.memory[pc+2])<<8 | uint16(c.memory[pc+3])
+			break
+		}
 		x := c.memory[pc] & 0x0F
 		switch opcode & 0x00FF {
 		// LD Vx, DT (Fx07)
@@ -336,6 +602,18 @@ func (c *Chip) nextOp() error {
 			// Wait for a key press, store the value of the key in Vx
 			c.v[x] = c.waitKey()
 			break
+		// PLANE x (Fx01): XO-CHIP select drawing/clear plane(s)
+		case 0x0001:
+			if c.mode == XOChip {
+				c.plane = x & 0x3
+			}
+			break
+		// LD AUDIO, [I] (Fx02): XO-CHIP load 16-byte audio pattern from I
+		case 0x0002:
+			if c.mode == XOChip {
+				copy(c.audioPattern[:], c.memory[c.i:c.i+16])
+			}
+			break
 		// LD DT, Vx (Fx15)
 		case 0x0015:
 			// Set delay timer = Vx
@@ -354,7 +632,13 @@ func (c *Chip) nextOp() error {
 		// LD F, Vx (Fx29)
 		case 0x0029:
 			// Set I = location of sprite for digit Vx
-			c.i = uint16(c.v[x]) * 5 // 5 bytes offset for every digit
+			c.i = fontOffset + uint16(c.v[x])*5 // 5 bytes offset for every digit
+			break
+		// LD HF, Vx (Fx30): SChip high-res font
+		case 0x0030:
+			if c.mode != Chip8 {
+				c.i = hiResFontOffset + uint16(c.v[x])*10
+			}
 			break
 		// LD B, Vx (Fx33)
 		case 0x0033:
@@ -373,6 +657,9 @@ func (c *Chip) nextOp() error {
 			for i := uint16(0); i <= last; i++ {
 				c.memory[c.i+i] = c.v[i]
 			}
+			if c.quirks.LoadStoreIncrementsI {
+				c.i += last + 1
+			}
 			break
 		// LD Vx, [I] (Fx65)
 		case 0x0065:
@@ -381,6 +668,25 @@ func (c *Chip) nextOp() error {
 			for i := uint16(0); i <= last; i++ {
 				c.v[i] = c.memory[c.i+i]
 			}
+			if c.quirks.LoadStoreIncrementsI {
+				c.i += last + 1
+			}
+			break
+		// LD R, Vx (Fx75): SChip/XO-CHIP RPL flag save
+		case 0x0075:
+			if c.mode != Chip8 {
+				if err := c.saveRPL(x); err != nil {
+					return err
+				}
+			}
+			break
+		// LD Vx, R (Fx85): SChip/XO-CHIP RPL flag restore
+		case 0x0085:
+			if c.mode != Chip8 {
+				if err := c.loadRPL(x); err != nil {
+					return err
+				}
+			}
 			break
 		default:
 			return unrecognizedOpcode(opcode)
@@ -396,30 +702,104 @@ func unrecognizedOpcode(opcode uint16) error {
 	return fmt.Errorf("unrecognized opcode %o", opcode)
 }
 
+// draw hands the current frame to whichever frontend the Chip was
+// constructed with. planes is a per-cell bitmask (bit 0 = plane 0, bit 1 =
+// plane 1); Chip8/SChip content only ever sets bit 0.
+// draw hands the current frame to the frontend, calling SetPixel only for
+// cells whose plane bitmask changed since the last call. A resolution
+// change invalidates the whole cache (the same index means a different
+// (x, y) once width changes), so the first frame after one redraws every
+// cell.
 func (c *Chip) draw() {
-	var width float64 = 64
+	width, height := c.width(), c.height()
+	resized := width != c.prevWidth || height != c.prevHeight
 
-	st := tcell.StyleDefault
-	black := st.Background(tcell.NewHexColor(0))
-	gray := st.Background(tcell.NewHexColor(0x444444))
+	for i := 0; i < width*height; i++ {
+		var planes byte
+		if c.planes[0][i] == 1 {
+			planes |= 0x1
+		}
+		if c.planes[1][i] == 1 {
+			planes |= 0x2
+		}
+		if resized || planes != c.prevFrame[i] {
+			c.frontend.SetPixel(i%width, i/width, planes)
+			c.prevFrame[i] = planes
+		}
+	}
+	c.prevWidth, c.prevHeight = width, height
+	c.frontend.Present()
+}
 
-	var glyph rune = '0'
+func (c *Chip) clearDisplay() {
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		for i := range c.planes[plane] {
+			c.planes[plane][i] = 0
+		}
+	}
+	c.drawFlag = true
+}
 
-	for i := range c.display {
-		x := i % 64
-		y := int(math.Floor(float64(i) / width))
+func (c *Chip) scrollDown(n byte) {
+	width, height := c.width(), c.height()
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		buf := c.planes[plane]
+		for y := height - 1; y >= 0; y-- {
+			for x := 0; x < width; x++ {
+				src := y - int(n)
+				if src >= 0 {
+					buf[y*width+x] = buf[src*width+x]
+				} else {
+					buf[y*width+x] = 0
+				}
+			}
+		}
+	}
+	c.drawFlag = true
+}
 
-		if c.display[i] == 1 {
-			c.screen.SetCell(x, y, black, glyph)
-		} else {
-			c.screen.SetCell(x, y, gray, glyph)
+func (c *Chip) scrollRight() {
+	width, height := c.width(), c.height()
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		buf := c.planes[plane]
+		for y := 0; y < height; y++ {
+			for x := width - 1; x >= 0; x-- {
+				if x >= 4 {
+					buf[y*width+x] = buf[y*width+x-4]
+				} else {
+					buf[y*width+x] = 0
+				}
+			}
 		}
 	}
+	c.drawFlag = true
 }
 
-func (c *Chip) clearDisplay() {
-	for i := range c.display {
-		c.display[i] = 0
+func (c *Chip) scrollLeft() {
+	width, height := c.width(), c.height()
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		buf := c.planes[plane]
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if x+4 < width {
+					buf[y*width+x] = buf[y*width+x+4]
+				} else {
+					buf[y*width+x] = 0
+				}
+			}
+		}
 	}
 	c.drawFlag = true
 }
@@ -430,39 +810,134 @@ func (c *Chip) clearDisplay() {
 // (00,32)-------------(64,32)
 func (c *Chip) displaySprite(x byte, y byte, i byte, n byte) {
 	c.v[0xF] = 0
+	width := byte(c.width())
 
-	for q := byte(0); q < n; q++ {
-		for p := byte(0); p < 8; p++ {
-			// 0x80 >> p takes a bit for every loop
-			// E.g.
-			// memory & 0b10000000 >> 1
-			pix := c.memory[i+q] & (0x80 >> p)
-			if pix != 0 {
-				tx := x + p
-				ty := y + q
-				if tx >= 64 {
-					tx -= 64
-				}
-				if ty >= 32 {
-					ty -= 32
-				}
+	planeIdx := c.i
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		for q := byte(0); q < n; q++ {
+			row := c.memory[planeIdx+uint16(q)]
+			for p := byte(0); p < 8; p++ {
+				// 0x80 >> p takes a bit for every loop
+				// E.g.
+				// memory & 0b10000000 >> 1
+				pix := row & (0x80 >> p)
+				if pix != 0 {
+					tx := x + p
+					ty := y + q
+					if tx >= width {
+						tx -= width
+					}
+					if ty >= byte(c.height()) {
+						ty -= byte(c.height())
+					}
+
+					idx := int(ty)*int(width) + int(tx)
 
-				// Set VF on collision...
-				if c.display[ty*64+x] == 1 {
-					c.v[0xF] = 1
+					// Set VF on collision...
+					if c.planes[plane][idx] == 1 {
+						c.v[0xF] = 1
+					}
+
+					c.planes[plane][idx] ^= 1
 				}
+			}
+		}
+		planeIdx += uint16(n)
+	}
+	c.drawFlag = true
+}
 
-				c.display[ty*64+x] ^= 1
+// displaySprite16 draws the SChip/XO-CHIP 16x16 sprite (Dxy0) located at I,
+// one selected plane at a time, 2 bytes (16 bits) per row for 16 rows.
+func (c *Chip) displaySprite16(x byte, y byte) {
+	c.v[0xF] = 0
+	width := byte(c.width())
+
+	planeIdx := c.i
+	for plane := 0; plane < 2; plane++ {
+		if c.plane&(1<<uint(plane)) == 0 {
+			continue
+		}
+		for q := byte(0); q < 16; q++ {
+			row := uint16(c.memory[planeIdx+uint16(q)*2])<<8 | uint16(c.memory[planeIdx+uint16(q)*2+1])
+			for p := byte(0); p < 16; p++ {
+				pix := row & (0x8000 >> p)
+				if pix != 0 {
+					tx := x + p
+					ty := y + q
+					if tx >= width {
+						tx -= width
+					}
+					if ty >= byte(c.height()) {
+						ty -= byte(c.height())
+					}
+
+					idx := int(ty)*int(width) + int(tx)
+
+					if c.planes[plane][idx] == 1 {
+						c.v[0xF] = 1
+					}
+
+					c.planes[plane][idx] ^= 1
+				}
 			}
 		}
+		planeIdx += 32
 	}
 	c.drawFlag = true
 }
 
+// storeRange implements XO-CHIP's 5xy2: store Vx through Vy (inclusive,
+// counting up or down depending on which register index is larger) to
+// memory starting at I. I itself is left unchanged.
+func (c *Chip) storeRange(x, y byte) {
+	if x <= y {
+		for n := x; n <= y; n++ {
+			c.memory[c.i+uint16(n-x)] = c.v[n]
+		}
+	} else {
+		for n := x; ; n-- {
+			c.memory[c.i+uint16(x-n)] = c.v[n]
+			if n == y {
+				break
+			}
+		}
+	}
+}
+
+// loadRange implements XO-CHIP's 5xy3: the inverse of storeRange.
+func (c *Chip) loadRange(x, y byte) {
+	if x <= y {
+		for n := x; n <= y; n++ {
+			c.v[n] = c.memory[c.i+uint16(n-x)]
+		}
+	} else {
+		for n := x; ; n-- {
+			c.v[n] = c.memory[c.i+uint16(x-n)]
+			if n == y {
+				break
+			}
+		}
+	}
+}
+
 func (c *Chip) isPressed(val byte) bool {
-	panic("not implemented yet!")
+	return c.keys[val] != 0
 }
 
+// waitKey blocks the calling (Cycle) goroutine until a key transitions
+// from up to down after waitKey starts listening, as fed by KeyDown via
+// c.keyPress, and returns that key's value. It's called from nextOp with
+// c.mu held, and releases it for the duration of the wait: a ROM parked
+// on Fx0A can otherwise wait indefinitely, which would stop tickTimers'
+// dt/st decrement dead and deadlock any concurrent Snapshot/Restore/Reset
+// call.
 func (c *Chip) waitKey() byte {
-	panic("not implemented yet!")
+	c.waitingKey = true
+	c.mu.Unlock()
+	defer c.mu.Lock()
+	return <-c.keyPress
 }
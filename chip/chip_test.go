@@ -0,0 +1,75 @@
+package chip
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luddd3/chip8/chip/io/headless"
+)
+
+// TestDisplaySpriteGoldenFramebuffer loads a known 3-row sprite at a known
+// (x, y) via a single DRW and asserts the resulting framebuffer exactly,
+// guarding against the sprite-plotting bug where a row's pixels all landed
+// in column x instead of spreading across x..x+7.
+func TestDisplaySpriteGoldenFramebuffer(t *testing.T) {
+	frontend := headless.New(1)
+	c := New(frontend, Options{Mode: Chip8, UseDefaultQuirks: true})
+
+	sprite := []byte{0b11100000, 0b10100000, 0b11100000}
+	copy(c.memory[0x300:], sprite)
+	c.i = 0x300
+	c.v[0] = 5                                 // x
+	c.v[1] = 2                                 // y
+	copy(c.memory[0x200:], []byte{0xD0, 0x13}) // DRW V0, V1, 3
+
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+
+	want := make([]byte, loResWidth*loResHeight)
+	for _, p := range [][2]int{
+		{5, 2}, {6, 2}, {7, 2},
+		{5, 3}, {7, 3},
+		{5, 4}, {6, 4}, {7, 4},
+	} {
+		want[p[1]*loResWidth+p[0]] = 1
+	}
+
+	got := frontend.LastFrame().Pixels
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("framebuffer mismatch:\ngot:  %v\nwant: %v", got, want)
+	}
+	if c.v[0xF] != 0 {
+		t.Fatalf("VF = %d, want 0 (nothing drawn there before)", c.v[0xF])
+	}
+}
+
+// TestDisplaySpriteCollision redraws the same sprite over itself, which
+// must XOR every lit pixel back off and set VF for the collision.
+func TestDisplaySpriteCollision(t *testing.T) {
+	frontend := headless.New(1)
+	c := New(frontend, Options{Mode: Chip8, UseDefaultQuirks: true})
+
+	sprite := []byte{0b11100000, 0b10100000, 0b11100000}
+	copy(c.memory[0x300:], sprite)
+	c.i = 0x300
+	c.v[0] = 5
+	c.v[1] = 2
+	copy(c.memory[0x200:], []byte{0xD0, 0x13, 0xD0, 0x13}) // DRW V0, V1, 3 twice
+
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step 1: %v", err)
+	}
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step 2: %v", err)
+	}
+
+	want := make([]byte, loResWidth*loResHeight)
+	got := frontend.LastFrame().Pixels
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("framebuffer mismatch after re-XOR:\ngot:  %v\nwant: %v", got, want)
+	}
+	if c.v[0xF] != 1 {
+		t.Fatalf("VF = %d, want 1 (redraw collided with itself)", c.v[0xF])
+	}
+}
@@ -0,0 +1,239 @@
+package chip
+
+// This file exposes the read/control surface a debugger (chip/debug)
+// needs without reaching into Chip's unexported fields directly: pausing
+// on breakpoints, inspecting registers/memory/the framebuffer, and
+// save-states via Snapshot/Restore.
+
+// AddBreakpoint pauses execution just before the instruction at pc runs.
+// A running Cycle blocks inside Step until Continue is called; AtBreak
+// reports the pc it stopped at.
+func (c *Chip) AddBreakpoint(pc uint16) {
+	c.breakpoints[pc] = true
+}
+
+// RemoveBreakpoint undoes AddBreakpoint.
+func (c *Chip) RemoveBreakpoint(pc uint16) {
+	delete(c.breakpoints, pc)
+}
+
+// HasBreakpoint reports whether pc currently pauses execution.
+func (c *Chip) HasBreakpoint(pc uint16) bool {
+	return c.breakpoints[pc]
+}
+
+// Breakpoints returns the currently set breakpoint addresses, in no
+// particular order.
+func (c *Chip) Breakpoints() []uint16 {
+	pcs := make([]uint16, 0, len(c.breakpoints))
+	for pc := range c.breakpoints {
+		pcs = append(pcs, pc)
+	}
+	return pcs
+}
+
+// AtBreak reports the pc execution most recently paused at, once per
+// breakpoint hit. A debugger front-end reads this to know when to
+// refresh its display; Continue resumes the paused goroutine.
+func (c *Chip) AtBreak() <-chan uint16 {
+	return c.atBreak
+}
+
+// Continue resumes execution paused at a breakpoint.
+func (c *Chip) Continue() {
+	c.resume <- struct{}{}
+}
+
+// Done is closed once Cycle returns, e.g. because the ROM halted via
+// 00FD. A debugger continuing past the last breakpoint should select on
+// this alongside AtBreak so it doesn't block forever on a ROM that never
+// hits another one.
+func (c *Chip) Done() <-chan struct{} {
+	return c.done
+}
+
+// awaitBreakpoint blocks the calling goroutine if pc has a breakpoint
+// set, until Continue is called. It runs before Step takes c.mu, so a
+// debugger reading state through the accessors below while paused never
+// contends with the paused Step call for the lock.
+func (c *Chip) awaitBreakpoint(pc uint16) {
+	if !c.breakpoints[pc] {
+		return
+	}
+	c.atBreak <- pc
+	<-c.resume
+}
+
+// The accessors below read Chip's execution state without taking c.mu:
+// they're meant for a debugger to call while the interpreter is paused
+// (at a breakpoint, or before Cycle has ever run), not while Cycle is
+// free-running.
+
+// PC returns the program counter.
+func (c *Chip) PC() uint16 { return c.pc }
+
+// SP returns the stack pointer.
+func (c *Chip) SP() byte { return c.sp }
+
+// I returns the address register.
+func (c *Chip) I() uint16 { return c.i }
+
+// DT returns the delay timer.
+func (c *Chip) DT() byte { return c.dt }
+
+// ST returns the sound timer.
+func (c *Chip) ST() byte { return c.st }
+
+// V returns a copy of registers V0-VF.
+func (c *Chip) V() []byte {
+	return append([]byte(nil), c.v...)
+}
+
+// Stack returns a copy of the call stack.
+func (c *Chip) Stack() []uint16 {
+	return append([]uint16(nil), c.stack...)
+}
+
+// Mem returns a copy of length bytes of memory starting at addr, clamped
+// to the end of the 4096-byte address space.
+func (c *Chip) Mem(addr uint16, length int) []byte {
+	end := int(addr) + length
+	if end > len(c.memory) {
+		end = len(c.memory)
+	}
+	if int(addr) >= end {
+		return nil
+	}
+	return append([]byte(nil), c.memory[addr:end]...)
+}
+
+// Width returns the display's current width in pixels (64 or 128).
+func (c *Chip) Width() int { return c.width() }
+
+// Height returns the display's current height in pixels (32 or 64).
+func (c *Chip) Height() int { return c.height() }
+
+// Framebuffer returns the currently displayed frame as a flat, row-major
+// slice of per-cell plane bitmasks, the same values Display.SetPixel
+// would be called with.
+func (c *Chip) Framebuffer() []byte {
+	w, h := c.width(), c.height()
+	buf := make([]byte, w*h)
+	for i := 0; i < w*h; i++ {
+		if c.planes[0][i] == 1 {
+			buf[i] |= 0x1
+		}
+		if c.planes[1][i] == 1 {
+			buf[i] |= 0x2
+		}
+	}
+	return buf
+}
+
+// Reset reinitializes registers, the stack, timers and the display back
+// to their power-on state and reloads the last ROM passed to LoadRom.
+// Breakpoints survive a Reset.
+func (c *Chip) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.memory {
+		c.memory[i] = 0
+	}
+	for i, b := range fontSet {
+		c.memory[fontOffset+i] = b
+	}
+	for i, b := range hiResFontSet {
+		c.memory[hiResFontOffset+i] = b
+	}
+	for i, b := range c.rom {
+		c.memory[0x200+i] = b
+	}
+
+	for i := range c.v {
+		c.v[i] = 0
+	}
+	for i := range c.stack {
+		c.stack[i] = 0
+	}
+	for p := range c.planes {
+		for i := range c.planes[p] {
+			c.planes[p][i] = 0
+		}
+	}
+	for i := range c.keys {
+		c.keys[i] = 0
+	}
+
+	c.plane = 1
+	c.hires = false
+	c.i = 0
+	c.pc = 0x200
+	c.sp = 0
+	c.dt, c.st = 0, 0
+	c.currentKey = 0
+	c.halted = false
+	c.drawFlag = true
+}
+
+// Snapshot is a serializable copy of a Chip's full execution state, for
+// save-states or for a debugger to compare against a later Snapshot.
+type Snapshot struct {
+	Memory []byte
+	Planes [2][]byte
+	Plane  byte
+	Hires  bool
+	Stack  []uint16
+	V      []byte
+	Keys   []byte
+	DT, ST byte
+	I      uint16
+	PC     uint16
+	SP     byte
+}
+
+// Snapshot copies out the Chip's full execution state.
+func (c *Chip) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Snapshot{
+		Memory: append([]byte(nil), c.memory...),
+		Planes: [2][]byte{
+			append([]byte(nil), c.planes[0]...),
+			append([]byte(nil), c.planes[1]...),
+		},
+		Plane: c.plane,
+		Hires: c.hires,
+		Stack: append([]uint16(nil), c.stack...),
+		V:     append([]byte(nil), c.v...),
+		Keys:  append([]byte(nil), c.keys...),
+		DT:    c.dt,
+		ST:    c.st,
+		I:     c.i,
+		PC:    c.pc,
+		SP:    c.sp,
+	}
+}
+
+// Restore replaces the Chip's execution state with s, as returned by an
+// earlier Snapshot.
+func (c *Chip) Restore(s Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	copy(c.memory, s.Memory)
+	copy(c.planes[0], s.Planes[0])
+	copy(c.planes[1], s.Planes[1])
+	c.plane = s.Plane
+	c.hires = s.Hires
+	c.stack = append([]uint16(nil), s.Stack...)
+	c.v = append([]byte(nil), s.V...)
+	c.keys = append([]byte(nil), s.Keys...)
+	c.dt = s.DT
+	c.st = s.ST
+	c.i = s.I
+	c.pc = s.PC
+	c.sp = s.SP
+	c.drawFlag = true
+}
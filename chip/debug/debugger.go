@@ -0,0 +1,247 @@
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/luddd3/chip8/chip"
+)
+
+// listingSize is how many upcoming instructions Debugger.print shows.
+const listingSize = 20
+
+// Debugger is an interactive, line-oriented front-end for a chip.Chip:
+// step/continue/break/watch/mem/reset, printing registers, the stack,
+// the next listingSize disassembled instructions and the framebuffer
+// before every prompt. The Chip is expected to be paused (Cycle not yet
+// started) when Run is called.
+type Debugger struct {
+	chip *chip.Chip
+	in   *bufio.Scanner
+	out  io.Writer
+
+	running bool // Cycle has been started via continue
+	watch   map[byte]bool
+}
+
+// New creates a Debugger that reads commands from in and writes its
+// display to out.
+func New(c *chip.Chip, in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{chip: c, in: bufio.NewScanner(in), out: out, watch: make(map[byte]bool)}
+}
+
+// Run prints the Chip's paused state and processes commands from in
+// until it hits EOF or the "quit" command.
+func (d *Debugger) Run() {
+	d.print()
+	for {
+		fmt.Fprint(d.out, "(chip8dbg) ")
+		if !d.in.Scan() {
+			return
+		}
+		fields := strings.Fields(d.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "step", "s":
+			d.step()
+		case "continue", "c":
+			d.continue_()
+		case "break", "b":
+			d.breakCmd(fields[1:])
+		case "watch", "w":
+			d.watchCmd(fields[1:])
+		case "mem", "m":
+			d.memCmd(fields[1:])
+		case "reset":
+			d.chip.Reset()
+			d.print()
+		case "quit", "q":
+			return
+		default:
+			fmt.Fprintf(d.out, "unknown command %q (try step, continue, break, watch, mem, reset, quit)\n", fields[0])
+		}
+	}
+}
+
+// step executes a single instruction. If the current PC has a
+// breakpoint set, it's temporarily removed so "step" always advances
+// exactly one instruction instead of re-pausing on the one it's
+// currently sitting on.
+func (d *Debugger) step() {
+	if d.running {
+		fmt.Fprintln(d.out, "continue has already started Cycle; use continue to reach the next breakpoint")
+		return
+	}
+
+	pc := d.chip.PC()
+	hadBreak := d.chip.HasBreakpoint(pc)
+	if hadBreak {
+		d.chip.RemoveBreakpoint(pc)
+	}
+
+	before := d.chip.V()
+	if err := d.chip.Step(); err != nil {
+		fmt.Fprintln(d.out, err)
+	}
+
+	if hadBreak {
+		d.chip.AddBreakpoint(pc)
+	}
+
+	d.reportWatch(before)
+	d.print()
+}
+
+// continue_ starts Cycle (on the first call) or resumes it past the
+// breakpoint it's paused at, then blocks until the next breakpoint hit
+// or the ROM halts.
+func (d *Debugger) continue_() {
+	if !d.running {
+		d.running = true
+		go d.chip.Cycle()
+	} else {
+		d.chip.Continue()
+	}
+
+	select {
+	case pc := <-d.chip.AtBreak():
+		fmt.Fprintf(d.out, "breakpoint hit at 0x%04X\n", pc)
+	case <-d.chip.Done():
+		fmt.Fprintln(d.out, "program halted")
+		d.running = false
+	}
+	d.print()
+}
+
+func (d *Debugger) breakCmd(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(d.out, "usage: break <addr>")
+		return
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		fmt.Fprintln(d.out, err)
+		return
+	}
+	d.chip.AddBreakpoint(addr)
+	fmt.Fprintf(d.out, "breakpoint set at 0x%04X\n", addr)
+}
+
+func (d *Debugger) watchCmd(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(d.out, "usage: watch <reg>  (e.g. watch v3)")
+		return
+	}
+	reg, err := parseReg(args[0])
+	if err != nil {
+		fmt.Fprintln(d.out, err)
+		return
+	}
+	d.watch[reg] = true
+	fmt.Fprintf(d.out, "watching V%X\n", reg)
+}
+
+func (d *Debugger) memCmd(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(d.out, "usage: mem <addr> <len>")
+		return
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		fmt.Fprintln(d.out, err)
+		return
+	}
+	length, err := strconv.Atoi(args[1])
+	if err != nil || length <= 0 {
+		fmt.Fprintln(d.out, "invalid length")
+		return
+	}
+
+	data := d.chip.Mem(addr, length)
+	for i := 0; i < len(data); i += 16 {
+		end := i + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		fmt.Fprintf(d.out, "0x%04X: % 02X\n", int(addr)+i, data[i:end])
+	}
+}
+
+// reportWatch prints any watched register that changed value across a
+// step, comparing against before (a V() snapshot taken just beforehand).
+func (d *Debugger) reportWatch(before []byte) {
+	after := d.chip.V()
+	for reg := range d.watch {
+		if before[reg] != after[reg] {
+			fmt.Fprintf(d.out, "V%X: 0x%02X -> 0x%02X\n", reg, before[reg], after[reg])
+		}
+	}
+}
+
+func (d *Debugger) print() {
+	v := d.chip.V()
+	fmt.Fprintln(d.out, "--- registers ---")
+	for i := 0; i < 16; i += 4 {
+		fmt.Fprintf(d.out, "V%X=%02X V%X=%02X V%X=%02X V%X=%02X\n",
+			i, v[i], i+1, v[i+1], i+2, v[i+2], i+3, v[i+3])
+	}
+	fmt.Fprintf(d.out, "I=%04X PC=%04X SP=%02X DT=%02X ST=%02X\n",
+		d.chip.I(), d.chip.PC(), d.chip.SP(), d.chip.DT(), d.chip.ST())
+
+	fmt.Fprintln(d.out, "--- stack ---")
+	fmt.Fprintln(d.out, d.chip.Stack())
+
+	fmt.Fprintln(d.out, "--- disassembly ---")
+	for _, ins := range Listing(d.chip.Mem(0, 4096), d.chip.PC(), listingSize) {
+		marker := "  "
+		if d.chip.HasBreakpoint(ins.Addr) {
+			marker = "* "
+		}
+		fmt.Fprintf(d.out, "%s0x%04X: %04X  %s\n", marker, ins.Addr, ins.Opcode, ins.Mnemonic)
+	}
+
+	fmt.Fprintln(d.out, "--- display ---")
+	d.printFramebuffer()
+}
+
+func (d *Debugger) printFramebuffer() {
+	w, h := d.chip.Width(), d.chip.Height()
+	buf := d.chip.Framebuffer()
+
+	var sb strings.Builder
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if buf[y*w+x] != 0 {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprint(d.out, sb.String())
+}
+
+func parseAddr(s string) (uint16, error) {
+	s = strings.TrimPrefix(strings.ToLower(s), "0x")
+	v, err := strconv.ParseUint(s, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q", s)
+	}
+	return uint16(v), nil
+}
+
+func parseReg(s string) (byte, error) {
+	s = strings.TrimPrefix(strings.ToLower(s), "v")
+	v, err := strconv.ParseUint(s, 16, 8)
+	if err != nil || v > 0xF {
+		return 0, fmt.Errorf("invalid register %q", s)
+	}
+	return byte(v), nil
+}
@@ -0,0 +1,172 @@
+// Package debug implements a disassembler and an interactive, line-oriented
+// debugger front-end for chip.Chip, reachable via main's -debug flag.
+package debug
+
+import "fmt"
+
+// Instruction pairs a decoded opcode with the address it was read from.
+type Instruction struct {
+	Addr     uint16
+	Opcode   uint16
+	Mnemonic string
+}
+
+// Listing decodes count two-byte instructions from mem starting at addr,
+// for a debugger's instruction view or a static disassembly dump. It
+// stops early if addr+count*2 runs past the end of mem.
+func Listing(mem []byte, addr uint16, count int) []Instruction {
+	out := make([]Instruction, 0, count)
+	for i := 0; i < count; i++ {
+		pc := addr + uint16(i*2)
+		if int(pc)+1 >= len(mem) {
+			break
+		}
+		opcode := uint16(mem[pc])<<8 | uint16(mem[pc+1])
+		out = append(out, Instruction{Addr: pc, Opcode: opcode, Mnemonic: Disassemble(opcode)})
+	}
+	return out
+}
+
+// Disassemble returns the human-readable mnemonic for a single opcode,
+// covering the Chip8/SChip/XO-CHIP opcode set nextOp understands (e.g.
+// 0x00E0 -> "CLS", 0xD125 -> "DRW V1, V2, 5"). Opcodes it doesn't
+// recognize are rendered as a raw data word.
+func Disassemble(opcode uint16) string {
+	x := byte((opcode & 0x0F00) >> 8)
+	y := byte((opcode & 0x00F0) >> 4)
+	n := byte(opcode & 0x000F)
+	kk := byte(opcode & 0x00FF)
+	nnn := opcode & 0x0FFF
+
+	switch opcode & 0xF000 {
+	case 0x0000:
+		if opcode&0x00F0 == 0x00C0 {
+			return fmt.Sprintf("SCD %d", n)
+		}
+		switch opcode {
+		case 0x00E0:
+			return "CLS"
+		case 0x00EE:
+			return "RET"
+		case 0x00FB:
+			return "SCR"
+		case 0x00FC:
+			return "SCL"
+		case 0x00FD:
+			return "EXIT"
+		case 0x00FE:
+			return "LOW"
+		case 0x00FF:
+			return "HIGH"
+		default:
+			return fmt.Sprintf("SYS %03X", nnn)
+		}
+	case 0x1000:
+		return fmt.Sprintf("JP %03X", nnn)
+	case 0x2000:
+		return fmt.Sprintf("CALL %03X", nnn)
+	case 0x3000:
+		return fmt.Sprintf("SE V%X, %02X", x, kk)
+	case 0x4000:
+		return fmt.Sprintf("SNE V%X, %02X", x, kk)
+	case 0x5000:
+		switch opcode & 0x000F {
+		case 0x0000:
+			return fmt.Sprintf("SE V%X, V%X", x, y)
+		case 0x0002:
+			return fmt.Sprintf("LD [I], V%X-V%X", x, y)
+		case 0x0003:
+			return fmt.Sprintf("LD V%X-V%X, [I]", x, y)
+		default:
+			return dataWord(opcode)
+		}
+	case 0x6000:
+		return fmt.Sprintf("LD V%X, %02X", x, kk)
+	case 0x7000:
+		return fmt.Sprintf("ADD V%X, %02X", x, kk)
+	case 0x8000:
+		switch opcode & 0x000F {
+		case 0x0000:
+			return fmt.Sprintf("LD V%X, V%X", x, y)
+		case 0x0001:
+			return fmt.Sprintf("OR V%X, V%X", x, y)
+		case 0x0002:
+			return fmt.Sprintf("AND V%X, V%X", x, y)
+		case 0x0003:
+			return fmt.Sprintf("XOR V%X, V%X", x, y)
+		case 0x0004:
+			return fmt.Sprintf("ADD V%X, V%X", x, y)
+		case 0x0005:
+			return fmt.Sprintf("SUB V%X, V%X", x, y)
+		case 0x0006:
+			return fmt.Sprintf("SHR V%X, V%X", x, y)
+		case 0x0007:
+			return fmt.Sprintf("SUBN V%X, V%X", x, y)
+		case 0x000E:
+			return fmt.Sprintf("SHL V%X, V%X", x, y)
+		default:
+			return dataWord(opcode)
+		}
+	case 0x9000:
+		return fmt.Sprintf("SNE V%X, V%X", x, y)
+	case 0xA000:
+		return fmt.Sprintf("LD I, %03X", nnn)
+	case 0xB000:
+		return fmt.Sprintf("JP V0, %03X", nnn)
+	case 0xC000:
+		return fmt.Sprintf("RND V%X, %02X", x, kk)
+	case 0xD000:
+		return fmt.Sprintf("DRW V%X, V%X, %d", x, y, n)
+	case 0xE000:
+		switch opcode & 0x00FF {
+		case 0x009E:
+			return fmt.Sprintf("SKP V%X", x)
+		case 0x00A1:
+			return fmt.Sprintf("SKNP V%X", x)
+		default:
+			return dataWord(opcode)
+		}
+	case 0xF000:
+		if opcode == 0xF000 {
+			return "LD I, nnnn"
+		}
+		switch opcode & 0x00FF {
+		case 0x0001:
+			return fmt.Sprintf("PLANE %d", x)
+		case 0x0002:
+			return "LD AUDIO, [I]"
+		case 0x0007:
+			return fmt.Sprintf("LD V%X, DT", x)
+		case 0x000A:
+			return fmt.Sprintf("LD V%X, K", x)
+		case 0x0015:
+			return fmt.Sprintf("LD DT, V%X", x)
+		case 0x0018:
+			return fmt.Sprintf("LD ST, V%X", x)
+		case 0x001E:
+			return fmt.Sprintf("ADD I, V%X", x)
+		case 0x0029:
+			return fmt.Sprintf("LD F, V%X", x)
+		case 0x0030:
+			return fmt.Sprintf("LD HF, V%X", x)
+		case 0x0033:
+			return fmt.Sprintf("LD B, V%X", x)
+		case 0x0055:
+			return fmt.Sprintf("LD [I], V%X", x)
+		case 0x0065:
+			return fmt.Sprintf("LD V%X, [I]", x)
+		case 0x0075:
+			return fmt.Sprintf("LD R, V%X", x)
+		case 0x0085:
+			return fmt.Sprintf("LD V%X, R", x)
+		default:
+			return dataWord(opcode)
+		}
+	default:
+		return dataWord(opcode)
+	}
+}
+
+func dataWord(opcode uint16) string {
+	return fmt.Sprintf("DW %04X", opcode)
+}
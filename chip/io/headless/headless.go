@@ -0,0 +1,97 @@
+// Package headless implements chip/io.Frontend without any real display or
+// input device, so the interpreter core can be driven and inspected from
+// automated tests.
+package headless
+
+import (
+	chipio "github.com/luddd3/chip8/chip/io"
+)
+
+// Frame is one rendered display frame. Each entry in Pixels is a bitmask
+// of which planes were lit at that cell (bit 0 = plane 0, bit 1 = plane
+// 1), row-major starting at (0, 0).
+type Frame struct {
+	Width, Height int
+	Pixels        []byte
+}
+
+// Backend is a chip/io.Frontend that renders into an in-memory ring
+// buffer of frames instead of a screen, and lets a test feed keypad
+// events and a quit request. None of its methods block.
+type Backend struct {
+	width, height int
+	pixels        []byte
+
+	frames    []Frame
+	maxFrames int
+
+	pending []chipio.KeyEvent
+	quit    bool
+}
+
+// New creates a headless backend that retains at most maxFrames completed
+// frames, dropping the oldest once full.
+func New(maxFrames int) *Backend {
+	return &Backend{maxFrames: maxFrames}
+}
+
+func (b *Backend) Resize(width, height int) {
+	b.width, b.height = width, height
+	b.pixels = make([]byte, width*height)
+}
+
+func (b *Backend) SetPixel(x, y int, planes byte) {
+	if b.pixels == nil {
+		return
+	}
+	b.pixels[y*b.width+x] = planes
+}
+
+func (b *Backend) Present() {
+	frame := Frame{Width: b.width, Height: b.height, Pixels: append([]byte(nil), b.pixels...)}
+	b.frames = append(b.frames, frame)
+	if len(b.frames) > b.maxFrames {
+		b.frames = b.frames[len(b.frames)-b.maxFrames:]
+	}
+}
+
+// Frames returns the frames currently retained, oldest first.
+func (b *Backend) Frames() []Frame {
+	return b.frames
+}
+
+// LastFrame returns the most recently presented frame, or the zero Frame
+// if nothing has been presented yet.
+func (b *Backend) LastFrame() Frame {
+	if len(b.frames) == 0 {
+		return Frame{}
+	}
+	return b.frames[len(b.frames)-1]
+}
+
+// Feed queues a key event for the next Poll call, letting a test drive
+// input deterministically.
+func (b *Backend) Feed(ev chipio.KeyEvent) {
+	b.pending = append(b.pending, ev)
+}
+
+func (b *Backend) Poll() []chipio.KeyEvent {
+	events := b.pending
+	b.pending = nil
+	return events
+}
+
+func (b *Backend) Start() {}
+func (b *Backend) Stop()  {}
+
+// RequestQuit marks the backend as wanting to exit, as later reported by
+// Quit.
+func (b *Backend) RequestQuit() {
+	b.quit = true
+}
+
+func (b *Backend) Quit() bool {
+	return b.quit
+}
+
+func (b *Backend) Close() {}
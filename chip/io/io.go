@@ -0,0 +1,57 @@
+// Package io defines the peripherals a chip8go frontend backend must
+// implement: a pixel Display, a 16-key Keypad and a one-bit Audio tone.
+// chip.Chip only ever talks to these interfaces, never to a concrete
+// backend, so the interpreter core can run headless, over tcell, or over
+// SDL2 without changes.
+package io
+
+// Display is the pixel-level rendering surface a backend provides to
+// chip.Chip.
+type Display interface {
+	// Resize is called whenever the interpreter's resolution changes,
+	// e.g. 64x32 <-> 128x64 via the SChip 00FE/00FF opcodes.
+	Resize(width, height int)
+	// SetPixel sets a single cell's state. planes is a bitmask of which
+	// display planes are lit at (x, y): bit 0 is plane 0, bit 1 is plane
+	// 1. Chip8/SChip only ever set bit 0; XO-CHIP may set either or
+	// both. Backends that can't render two planes distinctly may treat
+	// planes != 0 as simply "on".
+	SetPixel(x, y int, planes byte)
+	// Present flushes pending SetPixel calls to the screen/output.
+	Present()
+}
+
+// KeyEvent is a single key transition reported by a Keypad, using the
+// CHIP-8 keypad's own 0x0-0xF numbering.
+type KeyEvent struct {
+	Key  byte
+	Down bool
+}
+
+// Keypad reports transitions of the 16-key CHIP-8 keypad.
+type Keypad interface {
+	// Poll drains and returns key transitions observed since the last
+	// call, in the order they occurred.
+	Poll() []KeyEvent
+}
+
+// Audio plays, or silences, a continuous tone. Chip calls Start while the
+// sound timer is non-zero and Stop once it reaches zero.
+type Audio interface {
+	Start()
+	Stop()
+}
+
+// Frontend bundles the peripherals a backend must implement to drive a
+// Chip: a Display to draw to, a Keypad to read input from, and Audio for
+// the sound timer's beep.
+type Frontend interface {
+	Display
+	Keypad
+	Audio
+	// Quit reports whether the user has asked to exit (window closed,
+	// Escape pressed, ...).
+	Quit() bool
+	// Close releases any resources held by the backend.
+	Close()
+}
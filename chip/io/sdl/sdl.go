@@ -0,0 +1,185 @@
+// Package sdl implements chip/io.Frontend on top of veandco/go-sdl2,
+// giving chip8go a real window instead of a terminal, as several
+// reference CHIP-8 ports do.
+package sdl
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+
+	chipio "github.com/luddd3/chip8/chip/io"
+)
+
+// beepHz and beepSampleRate pick the tone Start/Stop play for st>0: a
+// plain 440Hz square wave, sampled at a rate every SDL2 audio driver
+// accepts.
+const (
+	beepHz         = 440
+	beepSampleRate = 44100
+)
+
+// scancodeMap maps PC keyboard scancodes onto the CHIP-8 keypad's 0x0-0xF
+// indices, using the layout most CHIP-8 ports settle on:
+//
+//	1 2 3 C        1 2 3 4
+//	4 5 6 D   -->  Q W E R
+//	7 8 9 E        A S D F
+//	A 0 B F        Z X C V
+var scancodeMap = map[sdl.Scancode]byte{
+	sdl.SCANCODE_1: 0x1, sdl.SCANCODE_2: 0x2, sdl.SCANCODE_3: 0x3, sdl.SCANCODE_4: 0xC,
+	sdl.SCANCODE_Q: 0x4, sdl.SCANCODE_W: 0x5, sdl.SCANCODE_E: 0x6, sdl.SCANCODE_R: 0xD,
+	sdl.SCANCODE_A: 0x7, sdl.SCANCODE_S: 0x8, sdl.SCANCODE_D: 0x9, sdl.SCANCODE_F: 0xE,
+	sdl.SCANCODE_Z: 0xA, sdl.SCANCODE_X: 0x0, sdl.SCANCODE_C: 0xB, sdl.SCANCODE_V: 0xF,
+}
+
+// Backend implements chip/io.Frontend with an SDL2 window. zoom scales
+// each CHIP-8 pixel up by that many screen pixels so the window stays a
+// usable size at both 64x32 and 128x64.
+type Backend struct {
+	window   *sdl.Window
+	renderer *sdl.Renderer
+	zoom     int
+	width    int
+	height   int
+	quit     bool
+	events   []chipio.KeyEvent
+
+	audioDevice sdl.AudioDeviceID
+	beepTick    []byte // one tickTimers-period's worth of beepHz square wave
+	beeping     bool
+}
+
+// New creates an SDL2 window sized for a width x height CHIP-8 display at
+// the given zoom factor.
+func New(width, height, zoom int) (*Backend, error) {
+	if err := sdl.Init(sdl.INIT_VIDEO | sdl.INIT_AUDIO); err != nil {
+		return nil, fmt.Errorf("sdl: init: %w", err)
+	}
+
+	window, err := sdl.CreateWindow("chip8go",
+		sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED,
+		int32(width*zoom), int32(height*zoom), sdl.WINDOW_SHOWN)
+	if err != nil {
+		sdl.Quit()
+		return nil, fmt.Errorf("sdl: create window: %w", err)
+	}
+
+	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
+	if err != nil {
+		window.Destroy()
+		sdl.Quit()
+		return nil, fmt.Errorf("sdl: create renderer: %w", err)
+	}
+
+	want := sdl.AudioSpec{Freq: beepSampleRate, Format: sdl.AUDIO_S16LSB, Channels: 1, Samples: 2048}
+	var got sdl.AudioSpec
+	audioDevice, err := sdl.OpenAudioDevice("", false, &want, &got, 0)
+	if err != nil {
+		renderer.Destroy()
+		window.Destroy()
+		sdl.Quit()
+		return nil, fmt.Errorf("sdl: open audio device: %w", err)
+	}
+
+	return &Backend{
+		window:      window,
+		renderer:    renderer,
+		zoom:        zoom,
+		width:       width,
+		height:      height,
+		audioDevice: audioDevice,
+		beepTick:    squareWave(got.Freq, beepHz, got.Freq/60),
+	}, nil
+}
+
+// squareWave renders nSamples of a freqHz square wave at sampleRate as
+// signed 16-bit little-endian PCM, the format tickTimers calls Start at
+// (once per 1/60s tick) expect QueueAudio to accept.
+func squareWave(sampleRate, freqHz int32, nSamples int32) []byte {
+	const amplitude = math.MaxInt16 / 4
+	buf := make([]byte, nSamples*2)
+	for i := int32(0); i < nSamples; i++ {
+		sample := int16(-amplitude)
+		if int(2*i*freqHz/sampleRate)%2 == 0 {
+			sample = amplitude
+		}
+		buf[i*2] = byte(sample)
+		buf[i*2+1] = byte(sample >> 8)
+	}
+	return buf
+}
+
+func (b *Backend) Resize(width, height int) {
+	b.width, b.height = width, height
+	b.window.SetSize(int32(width*b.zoom), int32(height*b.zoom))
+}
+
+func (b *Backend) SetPixel(x, y int, planes byte) {
+	switch {
+	case planes&0x3 == 0x3:
+		b.renderer.SetDrawColor(0, 0, 0, 255)
+	case planes&0x1 != 0:
+		b.renderer.SetDrawColor(0x44, 0x44, 0x44, 255)
+	case planes&0x2 != 0:
+		b.renderer.SetDrawColor(0x22, 0x44, 0xAA, 255)
+	default:
+		b.renderer.SetDrawColor(255, 255, 255, 255)
+	}
+	rect := sdl.Rect{X: int32(x * b.zoom), Y: int32(y * b.zoom), W: int32(b.zoom), H: int32(b.zoom)}
+	b.renderer.FillRect(&rect)
+}
+
+func (b *Backend) Present() {
+	b.renderer.Present()
+}
+
+func (b *Backend) Poll() []chipio.KeyEvent {
+	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+		switch e := event.(type) {
+		case *sdl.QuitEvent:
+			b.quit = true
+		case *sdl.KeyboardEvent:
+			key, ok := scancodeMap[e.Keysym.Scancode]
+			if !ok {
+				continue
+			}
+			b.events = append(b.events, chipio.KeyEvent{Key: key, Down: e.State == sdl.PRESSED})
+		}
+	}
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// Start plays a 440Hz square wave. tickTimers calls it once per tick
+// while st>0, so it queues one tick's worth of tone each time and only
+// unpauses the device on the silence-to-sound transition.
+func (b *Backend) Start() {
+	sdl.QueueAudio(b.audioDevice, b.beepTick)
+	if !b.beeping {
+		sdl.PauseAudioDevice(b.audioDevice, false)
+		b.beeping = true
+	}
+}
+
+// Stop silences the tone started by Start.
+func (b *Backend) Stop() {
+	if b.beeping {
+		sdl.PauseAudioDevice(b.audioDevice, true)
+		sdl.ClearQueuedAudio(b.audioDevice)
+		b.beeping = false
+	}
+}
+
+func (b *Backend) Quit() bool {
+	return b.quit
+}
+
+func (b *Backend) Close() {
+	sdl.CloseAudioDevice(b.audioDevice)
+	b.renderer.Destroy()
+	b.window.Destroy()
+	sdl.Quit()
+}
@@ -0,0 +1,204 @@
+// Package tcell adapts a github.com/gdamore/tcell Screen to chip/io's
+// Frontend interface. This is the backend main.go has always used; it now
+// just lives behind the Frontend seam instead of being wired into
+// chip.Chip directly.
+package tcell
+
+import (
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+
+	chipio "github.com/luddd3/chip8/chip/io"
+)
+
+// keyHoldDuration is how long a key is reported as held down after a
+// tcell.EventKey, since terminals only report key presses, never
+// releases. Fx0A/Ex9E/ExA1 see the key as down for this long before it
+// auto-releases.
+const keyHoldDuration = 100 * time.Millisecond
+
+var (
+	black = tcell.NewHexColor(0)
+	gray  = tcell.NewHexColor(0x444444)
+	blue  = tcell.NewHexColor(0x2244AA)
+)
+
+// colorFor maps a SetPixel plane bitmask onto the color it renders as,
+// matching SDL's palette for lit cells: both planes render black, plane 0
+// alone gray, plane 1 alone blue. Unlit cells also render black here,
+// where SDL draws them white.
+func colorFor(planes byte) tcell.Color {
+	switch {
+	case planes&0x1 != 0 && planes&0x2 == 0:
+		return gray
+	case planes&0x2 != 0 && planes&0x1 == 0:
+		return blue
+	default:
+		return black
+	}
+}
+
+// keyMap maps physical keys onto the CHIP-8 keypad's 0x0-0xF indices,
+// using the layout most CHIP-8 ports settle on:
+//
+//	1 2 3 C        1 2 3 4
+//	4 5 6 D   -->  Q W E R
+//	7 8 9 E        A S D F
+//	A 0 B F        Z X C V
+var keyMap = map[rune]byte{
+	'1': 0x1, '2': 0x2, '3': 0x3, '4': 0xC,
+	'q': 0x4, 'w': 0x5, 'e': 0x6, 'r': 0xD,
+	'a': 0x7, 's': 0x8, 'd': 0x9, 'f': 0xE,
+	'z': 0xA, 'x': 0x0, 'c': 0xB, 'v': 0xF,
+}
+
+// Backend implements chip/io.Frontend on top of an initialized
+// tcell.Screen. New starts a background goroutine that pumps
+// screen.PollEvent, since tcell has no non-blocking poll of its own.
+//
+// Two CHIP-8 display rows are packed into one terminal row using the
+// upper-half-block character (▀): its foreground is the top row's pixel,
+// its background the bottom row's, so a cell renders as two pixels
+// stacked vertically. scale duplicates each pixel horizontally, since a
+// terminal cell is taller than it is wide and an unscaled frame would
+// come out squashed.
+type Backend struct {
+	screen tcell.Screen
+	scale  int
+
+	width, height int
+	pixels        []byte // last SetPixel plane bitmask, row-major over width x height
+
+	mu      sync.Mutex
+	quit    bool
+	beeping bool
+	events  []chipio.KeyEvent
+}
+
+// New wraps an already-initialized tcell.Screen. scale duplicates each
+// CHIP-8 pixel horizontally by that many terminal columns; values below
+// 1 are treated as 1.
+func New(screen tcell.Screen, scale int) *Backend {
+	if scale < 1 {
+		scale = 1
+	}
+	b := &Backend{screen: screen, scale: scale}
+	go b.pump()
+	return b
+}
+
+func (b *Backend) pump() {
+	for {
+		ev := b.screen.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			switch ev.Key() {
+			case tcell.KeyEscape, tcell.KeyEnter:
+				b.mu.Lock()
+				b.quit = true
+				b.mu.Unlock()
+				return
+			case tcell.KeyCtrlL:
+				b.screen.Sync()
+			default:
+				if key, ok := keyMap[unicode.ToLower(ev.Rune())]; ok {
+					b.reportPress(key)
+				}
+			}
+		case *tcell.EventResize:
+			b.screen.Sync()
+		}
+	}
+}
+
+// reportPress queues a Down event, then a matching Up event after
+// keyHoldDuration since the terminal itself never tells us when the key
+// was released.
+func (b *Backend) reportPress(key byte) {
+	b.mu.Lock()
+	b.events = append(b.events, chipio.KeyEvent{Key: key, Down: true})
+	b.mu.Unlock()
+
+	time.AfterFunc(keyHoldDuration, func() {
+		b.mu.Lock()
+		b.events = append(b.events, chipio.KeyEvent{Key: key, Down: false})
+		b.mu.Unlock()
+	})
+}
+
+func (b *Backend) Resize(width, height int) {
+	b.width, b.height = width, height
+	b.pixels = make([]byte, width*height)
+	b.screen.Sync()
+}
+
+// SetPixel records planes for later use by Present; it doesn't touch the
+// screen directly since one terminal cell renders a pair of CHIP-8 rows.
+func (b *Backend) SetPixel(x, y int, planes byte) {
+	b.pixels[y*b.width+x] = planes
+}
+
+// Present redraws every terminal cell from the pixels recorded by
+// SetPixel since the last call, packing CHIP-8 rows 2*ty and 2*ty+1 into
+// terminal row ty.
+func (b *Backend) Present() {
+	for ty := 0; ty*2 < b.height; ty++ {
+		top := ty * 2
+		bottom := top + 1
+		for x := 0; x < b.width; x++ {
+			fg := colorFor(b.pixels[top*b.width+x])
+			bg := black
+			if bottom < b.height {
+				bg = colorFor(b.pixels[bottom*b.width+x])
+			}
+			style := tcell.StyleDefault.Foreground(fg).Background(bg)
+			for s := 0; s < b.scale; s++ {
+				b.screen.SetContent(x*b.scale+s, ty, '▀', nil, style)
+			}
+		}
+	}
+	b.screen.Show()
+}
+
+// Poll drains the key events queued by the pump goroutine since the last
+// call.
+func (b *Backend) Poll() []chipio.KeyEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// Start rings the terminal bell once per silence-to-sound transition.
+// Terminals have no way to sustain a tone, so this is the best
+// approximation of "playing while st > 0" available to this backend.
+func (b *Backend) Start() {
+	b.mu.Lock()
+	wasBeeping := b.beeping
+	b.beeping = true
+	b.mu.Unlock()
+
+	if !wasBeeping {
+		b.screen.Beep()
+	}
+}
+
+func (b *Backend) Stop() {
+	b.mu.Lock()
+	b.beeping = false
+	b.mu.Unlock()
+}
+
+func (b *Backend) Quit() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.quit
+}
+
+func (b *Backend) Close() {
+	b.screen.Fini()
+}
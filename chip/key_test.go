@@ -0,0 +1,144 @@
+package chip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luddd3/chip8/chip/io/headless"
+)
+
+// newTestChip returns a Chip wired to a headless frontend, with nothing
+// loaded past the reset state.
+func newTestChip() *Chip {
+	return New(headless.New(1), Options{Mode: Chip8, UseDefaultQuirks: true})
+}
+
+// pc itself doesn't advance past the current instruction on a plain
+// Step (nextOp leaves it alone unless the opcode jumps or skips), so a
+// taken Ex9E/ExA1 skip is observed as pc advancing by 2 and an untaken
+// one as pc staying put, not the usual "+2 baseline, +4 on skip".
+
+func TestSkipIfKeyPressed(t *testing.T) {
+	c := newTestChip()
+	c.v[3] = 0x5
+	c.KeyDown(0x5)
+	copy(c.memory[0x200:], []byte{0xE3, 0x9E}) // SKP V3
+
+	pcBefore := c.pc
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if c.pc != pcBefore+2 {
+		t.Fatalf("pc = %#x, want %#x (pressed key should skip)", c.pc, pcBefore+2)
+	}
+}
+
+func TestSkipIfKeyPressedNotSkippedWhenUp(t *testing.T) {
+	c := newTestChip()
+	c.v[3] = 0x5                               // key 5 never pressed
+	copy(c.memory[0x200:], []byte{0xE3, 0x9E}) // SKP V3
+
+	pcBefore := c.pc
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if c.pc != pcBefore {
+		t.Fatalf("pc = %#x, want %#x (unpressed key should not skip)", c.pc, pcBefore)
+	}
+}
+
+func TestSkipIfKeyNotPressed(t *testing.T) {
+	c := newTestChip()
+	c.v[3] = 0x5                               // key 5 never pressed
+	copy(c.memory[0x200:], []byte{0xE3, 0xA1}) // SKNP V3
+
+	pcBefore := c.pc
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if c.pc != pcBefore+2 {
+		t.Fatalf("pc = %#x, want %#x (unpressed key should skip)", c.pc, pcBefore+2)
+	}
+}
+
+func TestSkipIfKeyNotPressedNotSkippedWhenDown(t *testing.T) {
+	c := newTestChip()
+	c.v[3] = 0x5
+	c.KeyDown(0x5)
+	copy(c.memory[0x200:], []byte{0xE3, 0xA1}) // SKNP V3
+
+	pcBefore := c.pc
+	if err := c.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if c.pc != pcBefore {
+		t.Fatalf("pc = %#x, want %#x (pressed key should not skip)", c.pc, pcBefore)
+	}
+}
+
+// TestWaitKeyBlocksUntilKeyDown exercises the blocking Fx0A wait: Step
+// must not return until a key transitions down, and then must store that
+// key's value in Vx.
+func TestWaitKeyBlocksUntilKeyDown(t *testing.T) {
+	c := newTestChip()
+	copy(c.memory[0x200:], []byte{0xF0, 0x0A}) // LD V0, K
+
+	done := make(chan error, 1)
+	go func() { done <- c.Step() }()
+
+	select {
+	case <-done:
+		t.Fatal("Step returned before any key was pressed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.KeyDown(0x7)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Step still blocked on Fx0A after KeyDown")
+	}
+
+	if c.v[0] != 0x7 {
+		t.Fatalf("V0 = %#x, want 0x7", c.v[0])
+	}
+}
+
+// TestWaitKeyIgnoresStaleKeypress confirms a key pressed (and released)
+// long before Fx0A runs isn't handed to waitKey as a stale result: Fx0A
+// must still block until a key goes down after it starts waiting.
+func TestWaitKeyIgnoresStaleKeypress(t *testing.T) {
+	c := newTestChip()
+	c.KeyDown(0x3)
+	c.KeyUp(0x3)
+
+	copy(c.memory[0x200:], []byte{0xF0, 0x0A}) // LD V0, K
+
+	done := make(chan error, 1)
+	go func() { done <- c.Step() }()
+
+	select {
+	case <-done:
+		t.Fatal("Step returned on a stale keypress instead of blocking")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.KeyDown(0x9)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Step still blocked on Fx0A after KeyDown")
+	}
+
+	if c.v[0] != 0x9 {
+		t.Fatalf("V0 = %#x, want 0x9", c.v[0])
+	}
+}
@@ -0,0 +1,72 @@
+package chip
+
+// Mode selects which interpreter profile Chip emulates. The three profiles
+// share the same base opcode table but disagree on a handful of decode and
+// resolution details, so Mode (together with Quirks) is threaded through the
+// opcode dispatch instead of forking nextOp entirely.
+type Mode int
+
+const (
+	// Chip8 is the original COSMAC VIP interpreter: 64x32 mono display,
+	// without the 10 opcodes SChip adds or the 5 more XOChip adds on top.
+	Chip8 Mode = iota
+	// SChip is CHIP-48/SUPER-CHIP: adds the 128x64 hi-res mode, scrolling,
+	// the 16x16 sprite draw and RPL flag persistence.
+	SChip
+	// XOChip additionally adds a second display plane, the audio pattern
+	// buffer, register range save/load and a 16-bit long I load.
+	XOChip
+)
+
+// Quirks captures the handful of behaviours ROMs disagree on depending on
+// which original interpreter they were written against. The zero value
+// turns every quirk off; use DefaultQuirks(mode) to get sane defaults
+// for a given Mode and then override individual fields as needed.
+type Quirks struct {
+	// ShiftVxOnly makes 8xy6/8xyE shift Vx in place, ignoring Vy. When
+	// false (the Chip8 default) Vx is first set to Vy before shifting.
+	ShiftVxOnly bool
+	// LoadStoreIncrementsI makes Fx55/Fx65 leave I advanced past the last
+	// register written/read, matching the original COSMAC behaviour. When
+	// false (the SChip/XOChip default) I is left unchanged.
+	LoadStoreIncrementsI bool
+	// JumpWithVx makes Bxnn jump to xnn + Vx instead of nnn + V0.
+	JumpWithVx bool
+}
+
+// DefaultQuirks returns the conventional quirk set for mode.
+func DefaultQuirks(mode Mode) Quirks {
+	switch mode {
+	case Chip8:
+		return Quirks{
+			ShiftVxOnly:          false,
+			LoadStoreIncrementsI: true,
+			JumpWithVx:           false,
+		}
+	default: // SChip, XOChip
+		return Quirks{
+			ShiftVxOnly:          true,
+			LoadStoreIncrementsI: false,
+			JumpWithVx:           true,
+		}
+	}
+}
+
+// Options configures a Chip at construction time.
+type Options struct {
+	// Mode selects the interpreter profile. Defaults to Chip8.
+	Mode Mode
+	// Quirks is used verbatim, including its zero value, unless
+	// UseDefaultQuirks is set — so a caller that wants every quirk off
+	// can leave Quirks unset and UseDefaultQuirks false, and a caller
+	// that wants DefaultQuirks(Mode) sets UseDefaultQuirks instead of
+	// trying to guess and copy it in by hand.
+	Quirks Quirks
+	// UseDefaultQuirks, when true, ignores Quirks and uses
+	// DefaultQuirks(Mode) instead. Most callers want this set.
+	UseDefaultQuirks bool
+	// ClockHz sets the CPU's instruction rate in Hz. Leave zero to use
+	// defaultClockHz (500). The 60Hz delay/sound timers always decrement
+	// at their own fixed rate regardless of this setting.
+	ClockHz int
+}
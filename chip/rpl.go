@@ -0,0 +1,70 @@
+package chip
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// rplDir returns the directory RPL flag files are persisted under,
+// creating it if necessary.
+func rplDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(base, "chip8go", "rpl")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// rplFile returns the file backing a single ROM's RPL flags. SChip ROMs
+// keep up to 8 flags (v[0:8]) and XOChip ROMs up to 16 (v[0:16]); Fx75
+// saves v[0..x] for any x in either mode. We key the file on the ROM name
+// so different ROMs don't clobber each other's save.
+func rplFile(name string) (string, error) {
+	dir, err := rplDir()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(dir, name+".rpl"), nil
+}
+
+// saveRPL persists v[0:=last] to disk under c.romName (Fx75).
+func (c *Chip) saveRPL(last byte) error {
+	path, err := rplFile(c.romName)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, c.v[:int(last)+1], 0o644)
+}
+
+// loadRPL restores v[0:=last] from disk (Fx85). Missing save data is not an
+// error: the registers are simply left at their current value, matching
+// how RPL flags behave on real SChip hardware before the first save.
+func (c *Chip) loadRPL(last byte) error {
+	path, err := rplFile(c.romName)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := 0; i <= int(last) && i < len(data); i++ {
+		c.v[i] = data[i]
+	}
+	return nil
+}
@@ -1,60 +1,131 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/gdamore/tcell"
+
 	"github.com/luddd3/chip8/chip"
+	"github.com/luddd3/chip8/chip/debug"
+	chipio "github.com/luddd3/chip8/chip/io"
+	"github.com/luddd3/chip8/chip/io/headless"
+	"github.com/luddd3/chip8/chip/io/sdl"
+	chiptcell "github.com/luddd3/chip8/chip/io/tcell"
+)
+
+var (
+	backendFlag = flag.String("backend", "tcell", "display/input backend: tcell, sdl, or headless")
+	clockFlag   = flag.Int("clock", 500, "CPU instruction rate, in Hz")
+	debugFlag   = flag.Bool("debug", false, "pause on start and drive the interpreter from an interactive debugger instead of running freely")
+	scaleFlag   = flag.Int("scale", 2, "tcell backend only: duplicate each CHIP-8 pixel horizontally this many terminal columns, for wide terminals")
+	modeFlag    = flag.String("mode", "chip8", "interpreter profile: chip8, schip, or xochip")
 )
 
+// parseMode maps a -mode flag value onto a chip.Mode.
+func parseMode(mode string) (chip.Mode, error) {
+	switch mode {
+	case "chip8":
+		return chip.Chip8, nil
+	case "schip":
+		return chip.SChip, nil
+	case "xochip":
+		return chip.XOChip, nil
+	default:
+		return 0, fmt.Errorf("unknown mode %q", mode)
+	}
+}
+
 func main() {
-	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
-	screen, err := tcell.NewScreen()
-	screen.SetStyle(tcell.StyleDefault.
-		Foreground(tcell.ColorBlack).
-		Background(tcell.ColorWhite))
-	screen.Clear()
+	flag.Parse()
+
+	mode, err := parseMode(*modeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	backend := *backendFlag
+	if *debugFlag && backend != "headless" {
+		// debug.Debugger reads commands from os.Stdin and writes its REPL
+		// to os.Stdout; tcell's Init puts the terminal in raw mode and
+		// starts its own goroutine reading that same stdin, and sdl owns
+		// its own window, so both would fight the debugger over the
+		// terminal. headless has no such conflict.
+		fmt.Fprintf(os.Stderr, "-debug: forcing -backend=headless (was %q)\n", backend)
+		backend = "headless"
+	}
+
+	frontend, err := newFrontend(backend)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	if err = screen.Init(); err != nil {
+	defer frontend.Close()
+
+	c := chip.New(frontend, chip.Options{Mode: mode, UseDefaultQuirks: true, ClockHz: *clockFlag})
+	const romPath = "roms/tetris.ch8"
+	rom, err := ioutil.ReadFile(romPath)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+	c.LoadRom(filepath.Base(romPath), rom)
+
+	go pollInput(c, frontend)
+
+	if *debugFlag {
+		debug.New(c, os.Stdin, os.Stdout).Run()
+		return
+	}
+
+	go c.Cycle()
+	for !frontend.Quit() {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
 
-	screen.SetStyle(tcell.StyleDefault.
-		Foreground(tcell.ColorBlack).
-		Background(tcell.ColorWhite))
-	screen.Clear()
-
-	chip := chip.New(screen)
-	rom, err := ioutil.ReadFile("roms/tetris.ch8")
-	chip.LoadRom(rom)
-
-	go func() {
-		chip.Cycle()
-	}()
-
-	quit := make(chan struct{})
-	go func() {
-		for {
-			ev := screen.PollEvent()
-			switch ev := ev.(type) {
-			case *tcell.EventKey:
-				switch ev.Key() {
-				case tcell.KeyEscape, tcell.KeyEnter:
-					close(quit)
-					return
-				case tcell.KeyCtrlL:
-					screen.Sync()
-				}
-			case *tcell.EventResize:
-				screen.Sync()
+// pollInput feeds keypad transitions reported by frontend into c until
+// the frontend asks to quit. It runs in its own goroutine so it keeps
+// working while a -debug session blocks main on its own prompt.
+func pollInput(c *chip.Chip, frontend chipio.Frontend) {
+	for !frontend.Quit() {
+		for _, ev := range frontend.Poll() {
+			if ev.Down {
+				c.KeyDown(ev.Key)
+			} else {
+				c.KeyUp(ev.Key)
 			}
 		}
-	}()
-	<-quit
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newFrontend(backend string) (chipio.Frontend, error) {
+	switch backend {
+	case "tcell":
+		tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+		screen, err := tcell.NewScreen()
+		if err != nil {
+			return nil, err
+		}
+		if err := screen.Init(); err != nil {
+			return nil, err
+		}
+		screen.SetStyle(tcell.StyleDefault.
+			Foreground(tcell.ColorBlack).
+			Background(tcell.ColorWhite))
+		screen.Clear()
+		return chiptcell.New(screen, *scaleFlag), nil
+	case "sdl":
+		return sdl.New(64, 32, 10)
+	case "headless":
+		return headless.New(60), nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", backend)
+	}
 }